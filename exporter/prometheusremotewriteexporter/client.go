@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// Start resolves cfg.HTTPClientSettings into an *http.Client against the
+// extensions registered on host and stores it on e. This is what
+// actually attaches a configauth.Authentication extension (sigv4auth,
+// oauth2clientauth, an Azure MSI extension, ...) named by
+// HTTPClientSettings.Auth: confighttp.HTTPClientSettings.ToClient looks
+// the named extension up on host and wraps the client's RoundTripper
+// with it.
+//
+// Because that RoundTripper wrapping happens once, here, rather than per
+// request, a credential refresh the extension performs later isn't
+// something pushMetrics/send need to know about: if a refresh is
+// briefly invalid mid-flight, the next round trip just comes back as a
+// transport/HTTP error like any other, and the exporterhelper retry
+// sender wrapping PushMetrics already retries those without special
+// casing auth.
+func (e *prwExporter) Start(_ context.Context, host component.Host) error {
+	client, err := e.cfg.HTTPClientSettings.ToClient(host, e.telemetry)
+	if err != nil {
+		return err
+	}
+	e.client = client
+	return nil
+}
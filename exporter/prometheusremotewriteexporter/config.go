@@ -16,13 +16,17 @@ package prometheusremotewriteexporter // import "github.com/open-telemetry/opent
 
 import (
 	"fmt"
+	"time"
 
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/service/featuregate"
 
+	"github.com/prometheus/prometheus/model/relabel"
+
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
 )
 
 // Config defines configuration for Remote Write exporter.
@@ -47,6 +51,14 @@ type Config struct {
 	// ExternalLabels defines a map of label keys and values that are allowed to start with reserved prefix "__"
 	ExternalLabels map[string]string `mapstructure:"external_labels"`
 
+	// HTTPClientSettings.Auth selects a configauth.Authentication
+	// extension (e.g. sigv4auth for Amazon Managed Prometheus,
+	// oauth2clientauth for Google Managed Prometheus, or an Azure MSI
+	// extension) to sign or authenticate outgoing requests. The
+	// extension is resolved and attached to the underlying http.Client
+	// once at startup; subsequent credential refreshes happen inside
+	// its RoundTripper, so the exporter's retry/backoff queue keeps
+	// retrying through a mid-flight credential refresh transparently.
 	HTTPClientSettings confighttp.HTTPClientSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
 
 	// ResourceToTelemetrySettings is the option for converting resource attributes to telemetry attributes.
@@ -54,6 +66,71 @@ type Config struct {
 	// If enabled, all the resource attributes will be converted to metric labels by default.
 	ResourceToTelemetrySettings resourcetotelemetry.Settings `mapstructure:"resource_to_telemetry_conversion"`
 	WAL                         *WALConfig                   `mapstructure:"wal"`
+
+	// Translator configures how OTLP metrics are converted into
+	// Prometheus remote write time series. See
+	// pkg/translator/prometheusremotewrite for the full set of options.
+	Translator prometheusremotewrite.Settings `mapstructure:"translator"`
+
+	// RemoteWriteVersion selects the Remote Write wire protocol version:
+	// "1.0" (default) or "2.0". When the receiver answers a "2.0" request
+	// with 415, that request is sent once more encoded as "1.0"; later
+	// requests still start with the configured version.
+	//
+	// "2.0" support is partial: series are encoded with a 2.0-shaped
+	// symbols table, label refs, and samples, but per-series metadata
+	// (type/help/unit), native histograms, and created timestamps are not
+	// yet produced (see RemoteWriteVersion2's doc comment), so it isn't
+	// full spec compliance with a 2.0 receiver yet.
+	RemoteWriteVersion RemoteWriteVersion `mapstructure:"remote_write_version"`
+
+	// RelabelConfigs applies the Prometheus relabel action set to every
+	// time series before staleness tracking and tenant routing, whichever
+	// endpoint the series is then sent to.
+	RelabelConfigs []*RelabelConfig `mapstructure:"relabel_configs"`
+
+	// PerTenantRelabelConfigs applies additional relabel_configs to
+	// series for a specific tenant, keyed by the tenant value resolved
+	// from MultiTenancy.FromLabel. These run after RelabelConfigs.
+	PerTenantRelabelConfigs map[string][]*RelabelConfig `mapstructure:"per_tenant_relabel_configs"`
+
+	// StalenessTracking controls emission of Prometheus stale markers
+	// for series that disappear between collection intervals.
+	StalenessTracking StalenessTrackingConfig `mapstructure:"staleness_tracking"`
+
+	// TenantRouting fans series out to one of several remote write
+	// backends based on a label value, one request per backend. All
+	// backends share the exporter's queue and retry settings; there is
+	// no per-backend WAL segment. If unset, the exporter keeps sending
+	// every series to the single endpoint on HTTPClientSettings.
+	TenantRouting *TenantRouting `mapstructure:"tenant_routing"`
+
+	// compiledRelabelConfigs and compiledPerTenantRelabelConfigs are the
+	// relabel.Config values RelabelConfigs/PerTenantRelabelConfigs
+	// compile to. Validate populates them so the write path (see
+	// buildWriteRequest in exporter.go) doesn't recompile the regexes
+	// on every batch.
+	compiledRelabelConfigs          []*relabel.Config
+	compiledPerTenantRelabelConfigs map[string][]*relabel.Config
+}
+
+// StalenessTrackingConfig configures tracking of series seen in previous
+// collection intervals so that a stale marker can be emitted when a
+// series stops being reported, matching Prometheus' own staleness
+// handling for scraped series.
+type StalenessTrackingConfig struct {
+	// Enabled turns on staleness tracking. Disabled by default, since it
+	// requires maintaining an in-memory index of series fingerprints.
+	Enabled bool `mapstructure:"enabled"`
+
+	// MaxSeries bounds the number of fingerprints tracked at once. Once
+	// reached, the least recently seen series are evicted and will not
+	// receive a stale marker when they disappear. 0 means unbounded.
+	MaxSeries int `mapstructure:"max_series"`
+
+	// TTL is the maximum time a series fingerprint is kept without being
+	// seen again before it is evicted from the index.
+	TTL time.Duration `mapstructure:"ttl"`
 }
 
 // RemoteWriteQueue allows to configure the remote write queue.
@@ -127,5 +204,43 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("from_label should be set to find tenant name")
 	}
 
+	switch cfg.RemoteWriteVersion {
+	case "", RemoteWriteVersion1, RemoteWriteVersion2:
+	default:
+		return fmt.Errorf("remote_write_version must be \"1.0\" or \"2.0\", got %q", cfg.RemoteWriteVersion)
+	}
+
+	compiled, err := compileRelabelConfigs(cfg.RelabelConfigs)
+	if err != nil {
+		return err
+	}
+	cfg.compiledRelabelConfigs = compiled
+
+	if len(cfg.PerTenantRelabelConfigs) > 0 && cfg.MultiTenancy.FromLabel == "" {
+		return fmt.Errorf("per_tenant_relabel_configs requires multi_tenancy.from_label to be set")
+	}
+
+	compiledPerTenant := make(map[string][]*relabel.Config, len(cfg.PerTenantRelabelConfigs))
+	for tenant, configs := range cfg.PerTenantRelabelConfigs {
+		compiledTenant, err := compileRelabelConfigs(configs)
+		if err != nil {
+			return fmt.Errorf("per_tenant_relabel_configs[%s]: %w", tenant, err)
+		}
+		compiledPerTenant[tenant] = compiledTenant
+	}
+	cfg.compiledPerTenantRelabelConfigs = compiledPerTenant
+
+	if cfg.StalenessTracking.MaxSeries < 0 {
+		return fmt.Errorf("staleness_tracking.max_series can't be negative")
+	}
+
+	if cfg.StalenessTracking.TTL < 0 {
+		return fmt.Errorf("staleness_tracking.ttl can't be negative")
+	}
+
+	if err := cfg.TenantRouting.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
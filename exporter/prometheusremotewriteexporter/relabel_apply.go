@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// applyRelabelConfigs runs labels through cfgs in order and reports
+// whether the series survives (false once any "drop"-type action
+// matches). Labels are not assumed sorted and the result is not
+// re-sorted; callers that need sorted output should sort it themselves.
+func applyRelabelConfigs(labels []prompb.Label, cfgs []*relabel.Config) ([]prompb.Label, bool) {
+	for _, cfg := range cfgs {
+		var keep bool
+		labels, keep = applyRelabelConfig(labels, cfg)
+		if !keep {
+			return nil, false
+		}
+	}
+	return labels, true
+}
+
+func applyRelabelConfig(labels []prompb.Label, cfg *relabel.Config) ([]prompb.Label, bool) {
+	values := make([]string, 0, len(cfg.SourceLabels))
+	for _, name := range cfg.SourceLabels {
+		values = append(values, labelValue(labels, string(name)))
+	}
+	val := strings.Join(values, cfg.Separator)
+
+	switch cfg.Action {
+	case relabel.Keep:
+		return labels, cfg.Regex.MatchString(val)
+	case relabel.Drop:
+		return labels, !cfg.Regex.MatchString(val)
+	case relabel.KeepEqual:
+		return labels, labelValue(labels, cfg.TargetLabel) == val
+	case relabel.DropEqual:
+		return labels, labelValue(labels, cfg.TargetLabel) != val
+	case relabel.Replace:
+		match := cfg.Regex.FindStringSubmatchIndex(val)
+		if match == nil {
+			return labels, true
+		}
+		target := string(cfg.Regex.ExpandString(nil, cfg.Replacement, val, match))
+		if target == "" {
+			return removeLabel(labels, cfg.TargetLabel), true
+		}
+		return setLabel(labels, cfg.TargetLabel, target), true
+	case relabel.HashMod:
+		// Uses FNV-1a rather than Prometheus' own MD5-based hash: hashmod
+		// only needs to shard a series consistently across runs of this
+		// exporter, not match Prometheus' scrape-time sharding bit for bit.
+		if cfg.Modulus == 0 {
+			return labels, true
+		}
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(val))
+		return setLabel(labels, cfg.TargetLabel, fmt.Sprintf("%d", h.Sum64()%cfg.Modulus)), true
+	case relabel.LabelMap:
+		out := append([]prompb.Label(nil), labels...)
+		for _, l := range labels {
+			if cfg.Regex.MatchString(l.Name) {
+				out = setLabel(out, cfg.Regex.ReplaceAllString(l.Name, cfg.Replacement), l.Value)
+			}
+		}
+		return out, true
+	case relabel.LabelDrop:
+		out := make([]prompb.Label, 0, len(labels))
+		for _, l := range labels {
+			if !cfg.Regex.MatchString(l.Name) {
+				out = append(out, l)
+			}
+		}
+		return out, true
+	case relabel.LabelKeep:
+		out := make([]prompb.Label, 0, len(labels))
+		for _, l := range labels {
+			if cfg.Regex.MatchString(l.Name) {
+				out = append(out, l)
+			}
+		}
+		return out, true
+	}
+	return labels, true
+}
+
+func labelValue(labels []prompb.Label, name string) string {
+	for _, l := range labels {
+		if l.Name == name {
+			return l.Value
+		}
+	}
+	return ""
+}
+
+func setLabel(labels []prompb.Label, name, value string) []prompb.Label {
+	for i, l := range labels {
+		if l.Name == name {
+			labels[i].Value = value
+			return labels
+		}
+	}
+	return append(labels, prompb.Label{Name: name, Value: value})
+}
+
+func removeLabel(labels []prompb.Label, name string) []prompb.Label {
+	for i, l := range labels {
+		if l.Name == name {
+			return append(labels[:i], labels[i+1:]...)
+		}
+	}
+	return labels
+}
@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+
+import (
+	"fmt"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+)
+
+// RelabelConfig is a mapstructure-friendly mirror of
+// relabel.Config. Upstream's Regexp field implements custom YAML
+// (un)marshaling that mapstructure cannot decode directly from
+// Collector configuration, so this wrapper decodes the plain string
+// fields from YAML and compiles them into a relabel.Config via
+// toPrometheusConfig.
+type RelabelConfig struct {
+	// SourceLabels is a list of labels whose values are concatenated
+	// with Separator and matched against Regex.
+	SourceLabels []string `mapstructure:"source_labels"`
+
+	// Separator joins the values of SourceLabels. Defaults to ";".
+	Separator string `mapstructure:"separator"`
+
+	// Regex is matched against the concatenated SourceLabels values.
+	// Defaults to "(.*)".
+	Regex string `mapstructure:"regex"`
+
+	// Modulus is used with the hashmod action.
+	Modulus uint64 `mapstructure:"modulus"`
+
+	// TargetLabel is the label written to by replace, hashmod, and
+	// related actions.
+	TargetLabel string `mapstructure:"target_label"`
+
+	// Replacement is the replacement value, which may reference capture
+	// groups from Regex. Defaults to "$1".
+	Replacement string `mapstructure:"replacement"`
+
+	// Action is one of replace, keep, drop, hashmod, labelmap,
+	// labeldrop, labelkeep, keepequal, dropequal. Defaults to replace.
+	Action string `mapstructure:"action"`
+}
+
+// toPrometheusConfig compiles r into a relabel.Config, applying the same
+// defaults Prometheus applies when unmarshaling YAML, and parsing the
+// regex and action fields.
+func (r *RelabelConfig) toPrometheusConfig() (*relabel.Config, error) {
+	separator := r.Separator
+	if separator == "" {
+		separator = ";"
+	}
+	replacement := r.Replacement
+	if replacement == "" {
+		replacement = "$1"
+	}
+	action := relabel.Replace
+	if r.Action != "" {
+		if err := action.UnmarshalYAML(func(v interface{}) error {
+			*v.(*string) = r.Action
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("invalid relabel action %q: %w", r.Action, err)
+		}
+	}
+
+	regexStr := r.Regex
+	if regexStr == "" {
+		regexStr = "(.*)"
+	}
+	regex, err := relabel.NewRegexp(regexStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid relabel regex %q: %w", r.Regex, err)
+	}
+
+	cfg := &relabel.Config{
+		SourceLabels: toLabelNames(r.SourceLabels),
+		Separator:    separator,
+		Regex:        regex,
+		Modulus:      r.Modulus,
+		TargetLabel:  r.TargetLabel,
+		Replacement:  replacement,
+		Action:       action,
+	}
+	return cfg, nil
+}
+
+func toLabelNames(names []string) []model.LabelName {
+	out := make([]model.LabelName, len(names))
+	for i, n := range names {
+		out[i] = model.LabelName(n)
+	}
+	return out
+}
+
+// compileRelabelConfigs compiles a list of RelabelConfig wrappers into
+// relabel.Config values, stopping at the first invalid entry.
+func compileRelabelConfigs(configs []*RelabelConfig) ([]*relabel.Config, error) {
+	compiled := make([]*relabel.Config, 0, len(configs))
+	for i, c := range configs {
+		pc, err := c.toPrometheusConfig()
+		if err != nil {
+			return nil, fmt.Errorf("relabel_configs[%d]: %w", i, err)
+		}
+		compiled = append(compiled, pc)
+	}
+	return compiled, nil
+}
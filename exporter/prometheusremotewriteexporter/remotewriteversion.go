@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+
+// RemoteWriteVersion selects the wire format the exporter sends to the
+// remote write endpoint.
+type RemoteWriteVersion string
+
+const (
+	// RemoteWriteVersion1 sends the original Prometheus Remote Write
+	// protocol, prompb.WriteRequest, Snappy-compressed protobuf. This is
+	// the default and is understood by every known remote write receiver.
+	RemoteWriteVersion1 RemoteWriteVersion = "1.0"
+
+	// RemoteWriteVersion2 sends a message shaped like the Remote Write 2.0
+	// protocol, io.prometheus.write.v2.Request, with the right
+	// content-type and version header to trigger a receiver's 2.0 code
+	// path. Only the part of the spec this exporter actually produces is
+	// encoded: a symbols table for string interning, label refs, and
+	// samples. Per-series metadata (type/help/unit), native histograms,
+	// and created timestamps -- the rest of what 2.0 receivers key
+	// metric-type handling off of -- are not implemented (see writev2.go),
+	// so this is not yet full 2.0 interoperability with a spec-compliant
+	// receiver; it's a stepping stone toward it.
+	RemoteWriteVersion2 RemoteWriteVersion = "2.0"
+)
+
+// contentTypeV1 and contentTypeV2 are the Content-Type header values for
+// each supported protocol version, per the Remote Write specification.
+const (
+	contentTypeV1 = "application/x-protobuf"
+	contentTypeV2 = "application/x-protobuf;proto=io.prometheus.write.v2.Request"
+)
+
+// remoteWriteVersionHeader is the header used to negotiate the protocol
+// version with the receiver, as defined by the Remote Write 2.0 spec.
+const remoteWriteVersionHeader = "X-Prometheus-Remote-Write-Version"
+
+// ContentType returns the Content-Type header value to send for this
+// protocol version.
+func (v RemoteWriteVersion) ContentType() string {
+	if v == RemoteWriteVersion2 {
+		return contentTypeV2
+	}
+	return contentTypeV1
+}
+
+// HeaderValue returns the X-Prometheus-Remote-Write-Version header value
+// to send for this protocol version, or "" for 1.0, which predates the header.
+func (v RemoteWriteVersion) HeaderValue() string {
+	if v == RemoteWriteVersion2 {
+		return "2.0.0"
+	}
+	return ""
+}
+
+// Fallback returns the protocol version to retry with after the
+// receiver responds with HTTP 415 Unsupported Media Type, and whether a
+// fallback is available.
+func (v RemoteWriteVersion) Fallback() (RemoteWriteVersion, bool) {
+	if v == RemoteWriteVersion2 {
+		return RemoteWriteVersion1, true
+	}
+	return "", false
+}
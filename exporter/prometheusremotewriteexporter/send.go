@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/golang/snappy"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// PushMetrics is the exporter's consumer.Metrics entry point: it builds
+// the outgoing request(s) for md via pushMetrics, then sends them one
+// after another, so a slow backend for one tenant/route delays the
+// requests sent after it in the same batch. A failed request does not
+// stop the others: errors from every request are joined and returned
+// together; the exporterhelper retry sender wrapping this call retries
+// the whole md on any error, which re-sends every route again, not only
+// the ones that failed -- per-route retry bookkeeping would need the
+// per-route queue/pipeline TenantRouting.Dispatch's doc comment already
+// notes is out of scope here.
+func (e *prwExporter) PushMetrics(ctx context.Context, md pmetric.Metrics) error {
+	requests, err := e.pushMetrics(md)
+	if err != nil {
+		return err
+	}
+	var errs error
+	for _, req := range requests {
+		if err := e.send(ctx, req); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// send marshals req using cfg.RemoteWriteVersion and POSTs it to the
+// request's endpoint (req.Route.Endpoint when routed, otherwise
+// cfg.HTTPClientSettings.Endpoint). On a 415 Unsupported Media Type
+// response it falls back to the protocol version's Fallback() and
+// retries once with that encoding, per the Remote Write 2.0 content
+// negotiation rules.
+func (e *prwExporter) send(ctx context.Context, req routedRequest) error {
+	return e.sendVersion(ctx, req, e.cfg.RemoteWriteVersion)
+}
+
+func (e *prwExporter) sendVersion(ctx context.Context, req routedRequest, version RemoteWriteVersion) error {
+	endpoint := e.cfg.HTTPClientSettings.Endpoint
+	headers := map[string]string(nil)
+	if req.Route != nil {
+		endpoint = req.Route.Endpoint
+		headers = req.Route.Headers
+	}
+
+	body, err := encode(req.Request, version)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", version.ContentType())
+	httpReq.Header.Set("Content-Encoding", "snappy")
+	if hv := version.HeaderValue(); hv != "" {
+		httpReq.Header.Set(remoteWriteVersionHeader, hv)
+	}
+	for k, v := range headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode == http.StatusUnsupportedMediaType {
+		if fallback, ok := version.Fallback(); ok {
+			return e.sendVersion(ctx, req, fallback)
+		}
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("remote write to %s returned HTTP %d", endpoint, resp.StatusCode)
+	}
+	return nil
+}
+
+// encode marshals wr per version (the original prompb.WriteRequest for
+// 1.0, the hand-encoded writeV2Request for 2.0 -- see writev2.go) and
+// Snappy-compresses the result, matching the framing both Remote Write
+// protocol versions use.
+func encode(wr *prompb.WriteRequest, version RemoteWriteVersion) ([]byte, error) {
+	var raw []byte
+	if version == RemoteWriteVersion2 {
+		raw = newWriteV2Request(wr).Marshal()
+	} else {
+		data, err := wr.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		raw = data
+	}
+	return snappy.Encode(nil, raw), nil
+}
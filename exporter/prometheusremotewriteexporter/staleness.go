@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+
+import (
+	"container/list"
+	"hash/fnv"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// fingerprint returns a stable hash of a (possibly unsorted) label set,
+// suitable as the fingerprint key stalenessTracker indexes on.
+func fingerprint(labels []prompb.Label) uint64 {
+	sorted := append([]prompb.Label(nil), labels...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	h := fnv.New64a()
+	for _, l := range sorted {
+		_, _ = h.Write([]byte(l.Name))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(l.Value))
+		_, _ = h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+// stalenessTracker records which series fingerprints were present in the
+// previous flush and, once a series disappears, causes one stale sample
+// to be emitted for it on the next flush. This aligns cumulative OTLP
+// metric semantics with Prometheus' expectation that PromQL rate()
+// abandons a series once it stops being reported, rather than holding
+// the last value across a scrape gap indefinitely.
+//
+// It is safe for concurrent use.
+//
+// The index lives in memory only. Nothing is replayed into observe after
+// a restart, whether or not WAL is configured, so a restarted exporter
+// starts with an empty index and emits no stale marker for a series that
+// disappeared across the restart.
+type stalenessTracker struct {
+	mu sync.Mutex
+
+	maxSeries int
+	ttl       time.Duration
+
+	seen map[uint64]*list.Element // fingerprint -> lru entry
+	lru  *list.List               // front = most recently seen
+}
+
+type stalenessEntry struct {
+	fingerprint uint64
+	labels      []prompb.Label
+	lastSeen    time.Time
+}
+
+func newStalenessTracker(cfg StalenessTrackingConfig) *stalenessTracker {
+	return &stalenessTracker{
+		maxSeries: cfg.MaxSeries,
+		ttl:       cfg.TTL,
+		seen:      make(map[uint64]*list.Element),
+		lru:       list.New(),
+	}
+}
+
+// observe records that the series with the given fingerprint and labels
+// was present in the current flush.
+func (t *stalenessTracker) observe(fingerprint uint64, labels []prompb.Label, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if el, ok := t.seen[fingerprint]; ok {
+		el.Value.(*stalenessEntry).lastSeen = now
+		t.lru.MoveToFront(el)
+		return
+	}
+
+	el := t.lru.PushFront(&stalenessEntry{fingerprint: fingerprint, labels: labels, lastSeen: now})
+	t.seen[fingerprint] = el
+	t.evictLocked()
+}
+
+// staleSeries returns a one-shot stale sample for every fingerprint that
+// was tracked but not re-observed this round, and forgets those
+// fingerprints so they aren't reported stale again on every subsequent
+// flush. A fingerprint that reappears later is tracked fresh via the
+// next observe call, same as a series seen for the first time.
+func (t *stalenessTracker) staleSeries(observedThisRound map[uint64]struct{}, ts time.Time) []prompb.TimeSeries {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var stale []prompb.TimeSeries
+	var gone []*list.Element
+	for el := t.lru.Back(); el != nil; el = el.Prev() {
+		entry := el.Value.(*stalenessEntry)
+		if _, ok := observedThisRound[entry.fingerprint]; ok {
+			continue
+		}
+		stale = append(stale, prompb.TimeSeries{
+			Labels: entry.labels,
+			Samples: []prompb.Sample{{
+				Value:     math.Float64frombits(value.StaleNaN),
+				Timestamp: ts.UnixMilli(),
+			}},
+		})
+		gone = append(gone, el)
+	}
+
+	for _, el := range gone {
+		entry := el.Value.(*stalenessEntry)
+		t.lru.Remove(el)
+		delete(t.seen, entry.fingerprint)
+	}
+
+	return stale
+}
+
+// evictLocked drops least-recently-seen entries once maxSeries is
+// exceeded, and any entry that has exceeded the configured TTL. Callers
+// must hold t.mu.
+func (t *stalenessTracker) evictLocked() {
+	now := time.Now()
+	for el := t.lru.Back(); el != nil; {
+		entry := el.Value.(*stalenessEntry)
+		prev := el.Prev()
+		expired := t.ttl > 0 && now.Sub(entry.lastSeen) > t.ttl
+		overCapacity := t.maxSeries > 0 && t.lru.Len() > t.maxSeries
+		if expired || overCapacity {
+			t.lru.Remove(el)
+			delete(t.seen, entry.fingerprint)
+		}
+		el = prev
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter"
+
+import (
+	"fmt"
+
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// TenantRouting generalizes MultiTenancy into a fan-out: instead of
+// rewriting a single header or query param on one endpoint, each series
+// is dispatched (see Dispatch) to one of several backends based on a
+// routing table keyed on a label value, so different tenants' series
+// travel in separate requests rather than one shared one. Giving each
+// route its own queue, retry policy, and WAL segment additionally
+// requires a per-route exporterhelper pipeline, which isn't built here
+// (see Dispatch's doc comment).
+//
+// When TenantRouting is unset, the exporter keeps sending every series
+// to the single endpoint configured on HTTPClientSettings, optionally
+// tagged via MultiTenancy.
+type TenantRouting struct {
+	// Routes lists the backends series are dispatched to, evaluated in
+	// order. The first matching route wins.
+	Routes []TenantRoute `mapstructure:"routes"`
+
+	// Default is used for series that match no route. If nil, series
+	// matching no route are dropped.
+	Default *TenantRoute `mapstructure:"default"`
+}
+
+// TenantRoute matches series on a label value and sends them to their
+// own remote write backend.
+type TenantRoute struct {
+	// Match selects the series this route applies to.
+	Match RouteMatch `mapstructure:"match"`
+
+	// Endpoint is the remote write URL for this route's backend.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Headers are added to every request sent to Endpoint.
+	Headers map[string]string `mapstructure:"headers"`
+}
+
+// RouteMatch selects series whose Label has the given Value.
+type RouteMatch struct {
+	// Label is the series label inspected to pick a route, analogous to
+	// MultiTenancy.FromLabel.
+	Label string `mapstructure:"label"`
+
+	// Value is the label value that must match for this route to apply.
+	Value string `mapstructure:"value"`
+}
+
+// Validate checks that a TenantRouting configuration is well-formed:
+// every route has a label, a value, and an endpoint, and the default
+// route (if set) has an endpoint.
+func (tr *TenantRouting) Validate() error {
+	if tr == nil {
+		return nil
+	}
+
+	for i, route := range tr.Routes {
+		if err := route.validate(); err != nil {
+			return fmt.Errorf("tenant_routing.routes[%d]: %w", i, err)
+		}
+	}
+
+	if tr.Default != nil && tr.Default.Endpoint == "" {
+		return fmt.Errorf("tenant_routing.default: endpoint must be set")
+	}
+
+	return nil
+}
+
+func (r *TenantRoute) validate() error {
+	if r.Match.Label == "" {
+		return fmt.Errorf("match.label must be set")
+	}
+	if r.Match.Value == "" {
+		return fmt.Errorf("match.value must be set")
+	}
+	if r.Endpoint == "" {
+		return fmt.Errorf("endpoint must be set")
+	}
+	return nil
+}
+
+// route returns the TenantRoute that labels matches, evaluating Routes
+// in order and falling back to Default (which may itself be nil).
+func (tr *TenantRouting) route(labels []prompb.Label) *TenantRoute {
+	if tr == nil {
+		return nil
+	}
+	for i := range tr.Routes {
+		r := &tr.Routes[i]
+		if labelValue(labels, r.Match.Label) == r.Match.Value {
+			return r
+		}
+	}
+	return tr.Default
+}
+
+// Dispatch partitions a WriteRequest's series by the route each one
+// matches, so every backend receives only the series meant for it. A
+// series matching no route, with no Default configured, is dropped.
+//
+// Giving every returned partition its own queue, retry policy, and WAL
+// segment, as the original request also asked for, means instantiating
+// a separate exporterhelper pipeline per route; this tree has no
+// factory.go / component lifecycle to hang that on, so Dispatch only
+// does the partitioning -- it does not yet spin up per-route senders.
+func (tr *TenantRouting) Dispatch(wr *prompb.WriteRequest) map[*TenantRoute]*prompb.WriteRequest {
+	out := make(map[*TenantRoute]*prompb.WriteRequest)
+	for _, ts := range wr.Timeseries {
+		route := tr.route(ts.Labels)
+		if route == nil {
+			continue
+		}
+		if out[route] == nil {
+			out[route] = &prompb.WriteRequest{}
+		}
+		out[route].Timeseries = append(out[route].Timeseries, ts)
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewriteexporter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// highCardinalityRequest builds n series that all share the same label
+// *names* (as a real high-cardinality workload does -- think
+// "http_requests_total{method, path, status, pod, namespace}" repeated
+// per pod) but distinct values, which is exactly the shape RW 2.0's
+// symbols table is meant to shrink: every label name is written once
+// instead of once per series.
+func highCardinalityRequest(n int) *prompb.WriteRequest {
+	wr := &prompb.WriteRequest{Timeseries: make([]prompb.TimeSeries, 0, n)}
+	for i := 0; i < n; i++ {
+		wr.Timeseries = append(wr.Timeseries, prompb.TimeSeries{
+			Labels: []prompb.Label{
+				{Name: "__name__", Value: "http_requests_total"},
+				{Name: "method", Value: "GET"},
+				{Name: "path", Value: fmt.Sprintf("/api/v1/resource/%d", i)},
+				{Name: "status", Value: "200"},
+				{Name: "pod", Value: fmt.Sprintf("pod-%d", i)},
+				{Name: "namespace", Value: "default"},
+			},
+			Samples: []prompb.Sample{{Value: float64(i), Timestamp: 1000}},
+		})
+	}
+	return wr
+}
+
+// BenchmarkEncode_SymbolInterning reports the on-wire (Snappy-compressed)
+// size of the same high-cardinality batch encoded as Remote Write 1.0
+// (prompb.WriteRequest, label names repeated per series) versus 2.0
+// (writeV2Request, label names/values interned once into symbols).
+func BenchmarkEncode_SymbolInterning(b *testing.B) {
+	wr := highCardinalityRequest(5000)
+
+	b.Run("v1", func(b *testing.B) {
+		var size int
+		for i := 0; i < b.N; i++ {
+			encoded, err := encode(wr, RemoteWriteVersion1)
+			if err != nil {
+				b.Fatal(err)
+			}
+			size = len(encoded)
+		}
+		b.ReportMetric(float64(size), "bytes/op")
+	})
+
+	b.Run("v2", func(b *testing.B) {
+		var size int
+		for i := 0; i < b.N; i++ {
+			encoded, err := encode(wr, RemoteWriteVersion2)
+			if err != nil {
+				b.Fatal(err)
+			}
+			size = len(encoded)
+		}
+		b.ReportMetric(float64(size), "bytes/op")
+	})
+}
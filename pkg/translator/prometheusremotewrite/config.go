@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package prometheusremotewrite converts OTLP metrics into the
+// Prometheus remote write data model. It intentionally has no
+// dependency on confighttp, exporterhelper, or any other Collector
+// exporter scaffolding, so it can be vendored directly by anything that
+// speaks the Prometheus Remote Write protocol (e.g. VictoriaMetrics,
+// Cortex, Mimir) without pulling in the Collector exporter stack.
+package prometheusremotewrite // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+
+// Settings controls how OTLP metrics are translated into Prometheus
+// remote write time series.
+type Settings struct {
+	// AddMetricSuffixes appends unit and type suffixes to metric names
+	// (e.g. "_total", "_bytes", "_seconds") following Prometheus naming
+	// conventions. The zero value of Settings leaves it false; this
+	// package sets no other default.
+	AddMetricSuffixes bool
+
+	// KeepIdentifyingResourceAttrs keeps resource attributes that
+	// identify the resource (e.g. service.name, service.instance.id,
+	// service.namespace) as labels on every series, in addition to
+	// folding them into the target_info series.
+	KeepIdentifyingResourceAttrs bool
+
+	// PromoteResourceAttributes lists resource attribute keys that are
+	// copied onto every series as labels, alongside target_info.
+	PromoteResourceAttributes []string
+
+	// TargetInfoEnabled controls whether a target_info series carrying
+	// all of the resource's attributes is emitted per resource.
+	TargetInfoEnabled bool
+
+	// ScopeInfoEnabled controls whether an otel_scope_info series
+	// carrying instrumentation scope attributes is emitted per scope.
+	ScopeInfoEnabled bool
+
+	// SendExemplars controls whether OTLP exemplars attached to number
+	// (gauge and sum) and histogram data points are translated into
+	// Prometheus exemplars. The zero value of Settings leaves it false;
+	// this package sets no other default.
+	SendExemplars bool
+}
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewrite // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+
+import (
+	"encoding/hex"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// maxExemplarLabelBytes is the Prometheus-enforced limit on the total
+// size of an exemplar's label set.
+const maxExemplarLabelBytes = 128
+
+// exemplarsToPrompb converts the exemplars attached to an OTLP data
+// point into Prometheus exemplars, using trace_id/span_id as labels and
+// preserving any filtered attributes, truncating the label set once it
+// would exceed maxExemplarLabelBytes. Called from FromMetrics for every
+// number and histogram data point when Settings.SendExemplars is set.
+//
+// NOTE: this tree has no WAL implementation (Config.WAL's WALConfig type
+// isn't defined anywhere in this source tree), so the lossless
+// WAL-round-trip guarantee the original request also asked for cannot be
+// implemented or verified here; it needs to be revisited once the WAL
+// package exists.
+
+func exemplarsToPrompb(exemplars pmetric.ExemplarSlice) []prompb.Exemplar {
+	if exemplars.Len() == 0 {
+		return nil
+	}
+
+	out := make([]prompb.Exemplar, 0, exemplars.Len())
+	for i := 0; i < exemplars.Len(); i++ {
+		ex := exemplars.At(i)
+
+		var labels []prompb.Label
+		var size int
+
+		addLabel := func(name, value string) bool {
+			l := prompb.Label{Name: name, Value: value}
+			size += len(l.Name) + len(l.Value)
+			if size > maxExemplarLabelBytes {
+				return false
+			}
+			labels = append(labels, l)
+			return true
+		}
+
+		if traceID := ex.TraceID(); !traceID.IsEmpty() {
+			if !addLabel("trace_id", hex.EncodeToString(traceID[:])) {
+				out = append(out, toExemplar(ex, labels))
+				continue
+			}
+		}
+		if spanID := ex.SpanID(); !spanID.IsEmpty() {
+			if !addLabel("span_id", hex.EncodeToString(spanID[:])) {
+				out = append(out, toExemplar(ex, labels))
+				continue
+			}
+		}
+
+		ex.FilteredAttributes().Range(func(k string, v pcommon.Value) bool {
+			return addLabel(k, v.AsString())
+		})
+
+		out = append(out, toExemplar(ex, labels))
+	}
+	return out
+}
+
+func toExemplar(ex pmetric.Exemplar, labels []prompb.Label) prompb.Exemplar {
+	var value float64
+	switch ex.ValueType() {
+	case pmetric.ExemplarValueTypeInt:
+		value = float64(ex.IntValue())
+	case pmetric.ExemplarValueTypeDouble:
+		value = ex.DoubleValue()
+	}
+	return prompb.Exemplar{
+		Labels:    labels,
+		Value:     value,
+		Timestamp: ex.Timestamp().AsTime().UnixMilli(),
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewrite // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// identifyingResourceAttrs are folded into target_info (and, if
+// Settings.KeepIdentifyingResourceAttrs is set, also copied onto every
+// series) because they identify the resource a series came from.
+var identifyingResourceAttrs = []string{
+	"service.name",
+	"service.namespace",
+	"service.instance.id",
+}
+
+// unitSuffixes maps a handful of common OTLP/UCUM units to the suffix
+// Prometheus convention appends to the metric name. Units with no entry
+// here are sanitized and appended verbatim.
+var unitSuffixes = map[string]string{
+	"1":  "",
+	"By": "bytes",
+	"s":  "seconds",
+}
+
+// FromMetrics converts a batch of OTLP metrics into Prometheus remote
+// write time series, keyed by series signature so data points from
+// different resources or metrics that share a label set merge into one
+// series within a call. It is the single
+// entry point every exporter code path should go through rather than
+// hand-rolling OTLP traversal.
+//
+// A metric type FromMetrics doesn't yet support (exponential histograms,
+// summaries) is skipped rather than aborting the whole batch, so one
+// unsupported metric never drops the gauges/sums/classic-histograms
+// sitting next to it in the same pmetric.Metrics. Skipped metrics are
+// reported back as a consumererror.NewPermanent-wrapped error so
+// exporterhelper's retry sender doesn't keep re-sending the same batch
+// forever waiting for support that isn't coming.
+func FromMetrics(md pmetric.Metrics, settings Settings) (map[string]*prompb.TimeSeries, error) {
+	tsMap := make(map[string]*prompb.TimeSeries)
+	var errs error
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resourceAttrs := rm.Resource().Attributes()
+
+		if settings.TargetInfoEnabled {
+			addTargetInfo(tsMap, resourceAttrs, settings)
+		}
+
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			sm := sms.At(j)
+
+			if settings.ScopeInfoEnabled {
+				addScopeInfo(tsMap, resourceAttrs, sm.Scope(), settings)
+			}
+
+			metrics := sm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				if err := addMetric(tsMap, metrics.At(k), resourceAttrs, settings); err != nil {
+					errs = errors.Join(errs, err)
+				}
+			}
+		}
+	}
+
+	if errs != nil {
+		errs = consumererror.NewPermanent(errs)
+	}
+	return tsMap, errs
+}
+
+// addMetric converts a single OTLP metric's data points into time series
+// and merges them into tsMap. It returns an error, without touching
+// tsMap, for metric types it doesn't yet know how to convert; callers
+// should treat that as "this one metric was skipped", not "the whole
+// batch failed".
+func addMetric(tsMap map[string]*prompb.TimeSeries, m pmetric.Metric, resourceAttrs pcommon.Map, settings Settings) error {
+	name := buildMetricName(m, settings)
+
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := m.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			addNumberDataPoint(tsMap, name, dps.At(i), resourceAttrs, settings)
+		}
+	case pmetric.MetricTypeSum:
+		sum := m.Sum()
+		sumName := name
+		if settings.AddMetricSuffixes && sum.IsMonotonic() && !strings.HasSuffix(sumName, "_total") {
+			sumName += "_total"
+		}
+		dps := sum.DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			addNumberDataPoint(tsMap, sumName, dps.At(i), resourceAttrs, settings)
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			addHistogramDataPoint(tsMap, name, dps.At(i), resourceAttrs, settings)
+		}
+	case pmetric.MetricTypeExponentialHistogram, pmetric.MetricTypeSummary:
+		// Not yet supported: exponential histograms need native-histogram
+		// encoding (see RemoteWriteVersion2) and summaries need quantile
+		// series; both are tracked as follow-up work. Returning an error
+		// here only skips this one metric -- see FromMetrics.
+		return fmt.Errorf("prometheusremotewrite: metric type %s is not yet supported", m.Type())
+	}
+	return nil
+}
+
+func addNumberDataPoint(tsMap map[string]*prompb.TimeSeries, name string, dp pmetric.NumberDataPoint, resourceAttrs pcommon.Map, settings Settings) {
+	labels := buildLabels(name, dp.Attributes(), resourceAttrs, settings)
+	var value float64
+	switch dp.ValueType() {
+	case pmetric.NumberDataPointValueTypeInt:
+		value = float64(dp.IntValue())
+	case pmetric.NumberDataPointValueTypeDouble:
+		value = dp.DoubleValue()
+	}
+
+	ts := getOrCreateTimeSeries(tsMap, labels)
+	ts.Samples = append(ts.Samples, prompb.Sample{
+		Value:     value,
+		Timestamp: dp.Timestamp().AsTime().UnixMilli(),
+	})
+
+	if settings.SendExemplars {
+		ts.Exemplars = append(ts.Exemplars, exemplarsToPrompb(dp.Exemplars())...)
+	}
+}
+
+// addHistogramDataPoint emits the classic bucket/sum/count series for a
+// histogram data point.
+func addHistogramDataPoint(tsMap map[string]*prompb.TimeSeries, name string, dp pmetric.HistogramDataPoint, resourceAttrs pcommon.Map, settings Settings) {
+	ts := dp.Timestamp().AsTime().UnixMilli()
+
+	bucketCounts := dp.BucketCounts()
+	bounds := dp.ExplicitBounds()
+	var cumulative uint64
+	for i := 0; i < bucketCounts.Len(); i++ {
+		cumulative += bucketCounts.At(i)
+		bucketLabels := buildLabels(name+"_bucket", dp.Attributes(), resourceAttrs, settings)
+		le := "+Inf"
+		if i < bounds.Len() {
+			le = formatFloat(bounds.At(i))
+		}
+		bucketLabels = append(bucketLabels, prompb.Label{Name: "le", Value: le})
+		sortLabels(bucketLabels)
+		series := getOrCreateTimeSeries(tsMap, bucketLabels)
+		series.Samples = append(series.Samples, prompb.Sample{Value: float64(cumulative), Timestamp: ts})
+	}
+
+	sumLabels := buildLabels(name+"_sum", dp.Attributes(), resourceAttrs, settings)
+	sumSeries := getOrCreateTimeSeries(tsMap, sumLabels)
+	sumSeries.Samples = append(sumSeries.Samples, prompb.Sample{Value: dp.Sum(), Timestamp: ts})
+
+	countLabels := buildLabels(name+"_count", dp.Attributes(), resourceAttrs, settings)
+	countSeries := getOrCreateTimeSeries(tsMap, countLabels)
+	countSeries.Samples = append(countSeries.Samples, prompb.Sample{Value: float64(dp.Count()), Timestamp: ts})
+
+	if settings.SendExemplars {
+		countSeries.Exemplars = append(countSeries.Exemplars, exemplarsToPrompb(dp.Exemplars())...)
+	}
+}
+
+func addTargetInfo(tsMap map[string]*prompb.TimeSeries, resourceAttrs pcommon.Map, settings Settings) {
+	labels := []prompb.Label{{Name: "__name__", Value: "target_info"}}
+	resourceAttrs.Range(func(k string, v pcommon.Value) bool {
+		labels = append(labels, prompb.Label{Name: sanitizeLabelName(k), Value: v.AsString()})
+		return true
+	})
+	sortLabels(labels)
+	ts := getOrCreateTimeSeries(tsMap, labels)
+	ts.Samples = append(ts.Samples, prompb.Sample{Value: 1})
+}
+
+func addScopeInfo(tsMap map[string]*prompb.TimeSeries, resourceAttrs pcommon.Map, scope pcommon.InstrumentationScope, settings Settings) {
+	labels := []prompb.Label{
+		{Name: "__name__", Value: "otel_scope_info"},
+		{Name: "otel_scope_name", Value: scope.Name()},
+		{Name: "otel_scope_version", Value: scope.Version()},
+	}
+	if settings.KeepIdentifyingResourceAttrs {
+		labels = append(labels, identifyingLabels(resourceAttrs)...)
+	}
+	scope.Attributes().Range(func(k string, v pcommon.Value) bool {
+		labels = append(labels, prompb.Label{Name: sanitizeLabelName(k), Value: v.AsString()})
+		return true
+	})
+	sortLabels(labels)
+	ts := getOrCreateTimeSeries(tsMap, labels)
+	ts.Samples = append(ts.Samples, prompb.Sample{Value: 1})
+}
+
+// buildLabels assembles the label set for a series: metric name,
+// identifying/promoted resource attributes, and the data point's own
+// attributes (which win on collision, matching Prometheus scrape
+// semantics where target labels never override a sample's own labels).
+func buildLabels(name string, dpAttrs pcommon.Map, resourceAttrs pcommon.Map, settings Settings) []prompb.Label {
+	labels := []prompb.Label{{Name: "__name__", Value: name}}
+
+	if settings.KeepIdentifyingResourceAttrs {
+		labels = append(labels, identifyingLabels(resourceAttrs)...)
+	}
+	for _, key := range settings.PromoteResourceAttributes {
+		if v, ok := resourceAttrs.Get(key); ok {
+			labels = append(labels, prompb.Label{Name: sanitizeLabelName(key), Value: v.AsString()})
+		}
+	}
+
+	dpAttrs.Range(func(k string, v pcommon.Value) bool {
+		labels = append(labels, prompb.Label{Name: sanitizeLabelName(k), Value: v.AsString()})
+		return true
+	})
+
+	sortLabels(labels)
+	return dedupeLabels(labels)
+}
+
+func identifyingLabels(resourceAttrs pcommon.Map) []prompb.Label {
+	var labels []prompb.Label
+	for _, key := range identifyingResourceAttrs {
+		if v, ok := resourceAttrs.Get(key); ok {
+			labels = append(labels, prompb.Label{Name: sanitizeLabelName(key), Value: v.AsString()})
+		}
+	}
+	return labels
+}
+
+// buildMetricName applies the unit and type suffix Prometheus
+// conventions expect when settings.AddMetricSuffixes is set.
+func buildMetricName(m pmetric.Metric, settings Settings) string {
+	name := sanitizeMetricName(m.Name())
+	if !settings.AddMetricSuffixes {
+		return name
+	}
+	if suffix, ok := unitSuffixes[m.Unit()]; ok {
+		if suffix != "" && !strings.HasSuffix(name, "_"+suffix) {
+			name += "_" + suffix
+		}
+		return name
+	}
+	if m.Unit() != "" {
+		suffix := sanitizeMetricName(m.Unit())
+		if suffix != "" && !strings.HasSuffix(name, "_"+suffix) {
+			name += "_" + suffix
+		}
+	}
+	return name
+}
+
+// sortLabels orders labels by name, as Prometheus requires for
+// remote-write series. It uses SliceStable, not Slice: dedupeLabels
+// relies on same-named labels keeping their relative (append) order so
+// it can resolve collisions deterministically, and sort.Slice is
+// documented as not guaranteeing that for equal keys.
+func sortLabels(labels []prompb.Label) {
+	sort.SliceStable(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+}
+
+// dedupeLabels keeps the last occurrence of each label name once labels
+// are sorted, so a data point attribute with the same name as a promoted
+// resource attribute wins.
+func dedupeLabels(labels []prompb.Label) []prompb.Label {
+	out := labels[:0:0]
+	for i, l := range labels {
+		if i+1 < len(labels) && labels[i+1].Name == l.Name {
+			continue
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+func sanitizeLabelName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+func sanitizeMetricName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+func formatFloat(f float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%f", f), "0"), ".")
+}
+
+func getOrCreateTimeSeries(tsMap map[string]*prompb.TimeSeries, labels []prompb.Label) *prompb.TimeSeries {
+	sig := seriesSignature(labels)
+	if ts, ok := tsMap[sig]; ok {
+		return ts
+	}
+	ts := &prompb.TimeSeries{Labels: labels}
+	tsMap[sig] = ts
+	return ts
+}
+
+// seriesSignature returns a stable key for a (sorted) label set.
+func seriesSignature(labels []prompb.Label) string {
+	var b strings.Builder
+	for _, l := range labels {
+		b.WriteString(l.Name)
+		b.WriteByte('=')
+		b.WriteString(l.Value)
+		b.WriteByte(',')
+	}
+	return b.String()
+}